@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func intervalTree() cli.Command {
+
+	keyTypeFlag := cli.StringFlag{
+		Name:  "key",
+		Usage: "type that will be used for interval endpoints",
+	}
+	valTypeFlag := cli.StringFlag{
+		Name:  "val",
+		Usage: "interval payload type, exposing Min() and Max() key accessors",
+	}
+	genericsFlag := cli.BoolFlag{
+		Name: "generics",
+		Usage: "emit a Go 1.18+ generic IntervalTree[K, V] with a pluggable " +
+			"comparator instead of requiring a hand-written Compare method",
+	}
+
+	return cli.Command{
+		Name:      "interval-tree",
+		ShortName: "itree",
+		Usage:     "Create an augmented interval tree customized for your types.",
+		Description: `Create an interval tree customized for your types. The tree is
+built on the same left leaning red black balanced search tree as sorted-map,
+with each node augmented to track the maximum endpoint in its subtree. The
+key type must implement Compare(other KType) int, and the value type is an
+interval payload implementing Min() KType and Max() KType.`,
+		Flags: []cli.Flag{keyTypeFlag, valTypeFlag, genericsFlag, typeNameFlag("IntervalTree"), nodeNameFlag("ginode")},
+		Action: func(ctx *cli.Context) {
+			if ctx.Bool(genericsFlag.Name) {
+				genericIntervalTree(ctx)
+				return
+			}
+
+			ktype := valOrDefault(ctx, keyTypeFlag)
+			vtype := valOrDefault(ctx, valTypeFlag)
+
+			kname := ktype
+			vname := vtype
+			if len(kname) > 1 && []byte(kname)[0] == '*' {
+				kname = kname[1:]
+			}
+			if len(vname) > 1 && []byte(vname)[0] == '*' {
+				vname = vname[1:]
+			}
+			typeName := fmt.Sprintf("Interval%sTree", strings.Title(kname))
+			nodeName := fmt.Sprintf("inode%sTo%s", strings.Title(kname), strings.Title(vname))
+
+			cwd, _ := os.Getwd()
+			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
+
+			src := []byte(redblackbstIntervalSrc)
+			src = bytes.Replace(src, []byte("package redblackbst"), []byte(pkgname), 1)
+
+			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+
+			// need to replace Compare before replacing KType
+			src = replaceRbstCompareFunc(ktype, "IntervalTree", src)
+			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
+			src = bytes.Replace(src, []byte("VType"), []byte(vtype), -1)
+			src = bytes.Replace(src, []byte("IntervalTree"), []byte(typeName), -1)
+			src = bytes.Replace(src, []byte("inode"), []byte(nodeName), -1)
+
+			fmt.Println(string(src))
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func persistentMap() cli.Command {
+
+	keyTypeFlag := cli.StringFlag{
+		Name:  "key",
+		Usage: "type that will be used for keys",
+	}
+	valTypeFlag := cli.StringFlag{
+		Name:  "val",
+		Usage: "type that will be used for values",
+	}
+
+	return cli.Command{
+		Name:      "persistent-map",
+		ShortName: "pmap",
+		Usage:     "Create a persistent (immutable) sorted map customized for your types.",
+		Description: `Create a persistent sorted map customized for your types. Like
+sorted-map, it's built on a left leaning red black balanced search tree, but
+every Set/Delete returns a new map value that shares unmodified subtrees with
+the old one instead of mutating in place. A Builder is also emitted for fast
+transient bulk loads.`,
+		Flags: []cli.Flag{keyTypeFlag, valTypeFlag},
+		Action: func(ctx *cli.Context) {
+			ktype := valOrDefault(ctx, keyTypeFlag)
+			vtype := valOrDefault(ctx, valTypeFlag)
+
+			kname := ktype
+			vname := vtype
+			if len(kname) > 1 && []byte(kname)[0] == '*' {
+				kname = kname[1:]
+			}
+			if len(kname) > 2 && kname[:2] == "[]" {
+				kname = strings.Title(kname[2:]) + "s"
+			}
+			if len(vname) > 1 && []byte(vname)[0] == '*' {
+				vname = vname[1:]
+			}
+			if len(vname) > 2 && vname[:2] == "[]" {
+				vname = strings.Title(vname[2:]) + "s"
+			}
+			typeName := fmt.Sprintf("Persistent%sTo%sMap", strings.Title(kname), strings.Title(vname))
+			nodeName := fmt.Sprintf("pnode%sTo%s", strings.Title(kname), strings.Title(vname))
+			iterName := fmt.Sprintf("Persistent%sTo%sIterator", strings.Title(kname), strings.Title(vname))
+			builderName := fmt.Sprintf("Persistent%sTo%sBuilder", strings.Title(kname), strings.Title(vname))
+
+			cwd, _ := os.Getwd()
+			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
+
+			src := []byte(redblackbstPersistentSrc)
+			src = bytes.Replace(src, []byte("package redblackbst"), []byte(pkgname), 1)
+
+			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+
+			// need to replace Compare before replacing KType
+			src = replaceRbstCompareFunc(ktype, "PersistentMap", src)
+			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
+			src = bytes.Replace(src, []byte("VType"), []byte(vtype), -1)
+			src = bytes.Replace(src, []byte("PersistentMap"), []byte(typeName), -1)
+			src = bytes.Replace(src, []byte("Iterator"), []byte(iterName), -1)
+			src = bytes.Replace(src, []byte("Builder"), []byte(builderName), -1)
+			src = bytes.Replace(src, []byte("pnode"), []byte(nodeName), -1)
+
+			fmt.Println(string(src))
+		},
+	}
+}
@@ -21,6 +21,11 @@ func sortedMap() cli.Command {
 		Name:  "val",
 		Usage: "type that will be used for values",
 	}
+	genericsFlag := cli.BoolFlag{
+		Name: "generics",
+		Usage: "emit a Go 1.18+ generic SortedMap[K, V] with a pluggable " +
+			"comparator instead of requiring a hand-written Compare method",
+	}
 
 	return cli.Command{
 		Name:      "sorted-map",
@@ -30,8 +35,13 @@ func sortedMap() cli.Command {
 on a left leaning red black balanced search tree. The implementation has good
 performance and is well tested, with 100% test coverage. (the tests are not
 generated with the custom type)`,
-		Flags: []cli.Flag{keyTypeFlag, valTypeFlag},
+		Flags: []cli.Flag{keyTypeFlag, valTypeFlag, genericsFlag, typeNameFlag("SortedMap"), nodeNameFlag("gnode")},
 		Action: func(ctx *cli.Context) {
+			if ctx.Bool(genericsFlag.Name) {
+				genericSortedMap(ctx)
+				return
+			}
+
 			ktype := valOrDefault(ctx, keyTypeFlag)
 			vtype := valOrDefault(ctx, valTypeFlag)
 
@@ -51,6 +61,7 @@ generated with the custom type)`,
 			}
 			typeName := fmt.Sprintf("Sorted%sTo%sMap", strings.Title(kname), strings.Title(vname))
 			nodeName := fmt.Sprintf("node%sTo%s", strings.Title(kname), strings.Title(vname))
+			iterName := fmt.Sprintf("Sorted%sTo%sIterator", strings.Title(kname), strings.Title(vname))
 
 			cwd, _ := os.Getwd()
 			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
@@ -61,30 +72,38 @@ generated with the custom type)`,
 			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
 
 			// need to replace Compare before replacing KType
-			src = replaceRbstCompareFunc(ktype, src)
+			src = replaceRbstCompareFunc(ktype, "RedBlack", src)
 			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
 			src = bytes.Replace(src, []byte("VType"), []byte(vtype), -1)
 			src = bytes.Replace(src, []byte("RedBlack"), []byte(typeName), -1)
 			src = bytes.Replace(src, []byte("mapnode"), []byte(nodeName), -1)
+			src = bytes.Replace(src, []byte("Iterator"), []byte(iterName), -1)
 
 			fmt.Println(string(src))
 		},
 	}
 }
 
-func replaceRbstCompareFunc(ktype string, src []byte) []byte {
+// replaceRbstCompareFunc rewrites the "func (r <structName>) compare(a, b
+// KType) int { return a.Compare(b) }" indirection that the map and set
+// templates both use to resolve comparisons, so that primitive key types
+// (which have no Compare method of their own) get a generated comparison
+// instead of failing to build. structName must match the receiver type
+// used by the template being processed (e.g. "RedBlack" for sorted-map,
+// "RedBlackSet" for sorted-set).
+func replaceRbstCompareFunc(ktype, structName string, src []byte) []byte {
 	var tmpl string
-	orig := "func (r RedBlack) compare(a, b KType) int { return a.Compare(b) }"
+	orig := fmt.Sprintf("func (r %s) compare(a, b KType) int { return a.Compare(b) }", structName)
 
 	switch ktype {
 
 	case "int", "int8", "int16", "int32", "int64",
 		"uint", "uint8", "uint16", "uint32", "uint64":
-		tmpl = "func (r RedBlack) compare(a, b KType) int { return int(a) - int(b) }"
+		tmpl = fmt.Sprintf("func (r %s) compare(a, b KType) int { return int(a) - int(b) }", structName)
 
 	case "float32", "float64":
-		tmpl = `
-func (r RedBlack) compare(a, b KType) int {
+		tmpl = fmt.Sprintf(`
+func (r %s) compare(a, b KType) int {
 	const e = 0.00000001
 
     diff := (a-b)/a
@@ -94,11 +113,11 @@ func (r RedBlack) compare(a, b KType) int {
         return 1
     }
     return 0
-}`
+}`, structName)
 
 	case "string":
-		tmpl = `
-func (r RedBlack) compare(a, b KType) int {
+		tmpl = fmt.Sprintf(`
+func (r %s) compare(a, b KType) int {
     if a < b {
         return -1
     }
@@ -106,15 +125,15 @@ func (r RedBlack) compare(a, b KType) int {
         return 1
     }
     return 0
-}`
+}`, structName)
 
 	case "[]byte":
 		log.Printf("WARNING: using []byte as keys can lead to undefined behavior if the []byte are modified after insertion!!!")
-		tmpl = `import "bytes"
+		tmpl = fmt.Sprintf(`import "bytes"
 
 // WARNING: using []byte as keys can lead to undefined behavior if the
 // []byte are modified after insertion!!!
-func (r RedBlack) compare(a, b KType) int { return bytes.Compare(a, b) }`
+func (r %s) compare(a, b KType) int { return bytes.Compare(a, b) }`, structName)
 
 	default:
 
@@ -122,8 +141,8 @@ func (r RedBlack) compare(a, b KType) int { return bytes.Compare(a, b) }`
 		if len(ktype) > 2 && ktype[:2] == "[]" {
 			log.Printf("%s: order will be determined based on value of len(%s)", ktype, ktype)
 			tmpl = fmt.Sprintf(
-				"func (r RedBlack) compare(a, b %s) int { return len(a)-len(b) }",
-				ktype,
+				"func (r %s) compare(a, b %s) int { return len(a)-len(b) }",
+				structName, ktype,
 			)
 		} else {
 			l := 0
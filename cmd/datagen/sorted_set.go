@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func sortedSet() cli.Command {
+
+	keyTypeFlag := cli.StringFlag{
+		Name:  "key",
+		Usage: "type that will be used for elements",
+	}
+	genericsFlag := cli.BoolFlag{
+		Name: "generics",
+		Usage: "emit a Go 1.18+ generic SortedSet[K] with a pluggable " +
+			"comparator instead of requiring a hand-written Compare method",
+	}
+
+	return cli.Command{
+		Name:      "sorted-set",
+		ShortName: "sset",
+		Usage:     "Create a sorted set customized for your types.",
+		Description: `Create a sorted set customized for your types. The set is built
+on the same left leaning red black balanced search tree as sorted-map, but
+without carrying a value alongside each key.`,
+		Flags: []cli.Flag{keyTypeFlag, genericsFlag, typeNameFlag("SortedSet"), nodeNameFlag("gsnode")},
+		Action: func(ctx *cli.Context) {
+			if ctx.Bool(genericsFlag.Name) {
+				genericSortedSet(ctx)
+				return
+			}
+
+			ktype := valOrDefault(ctx, keyTypeFlag)
+
+			kname := ktype
+			if len(kname) > 1 && []byte(kname)[0] == '*' {
+				kname = kname[1:]
+			}
+			if len(kname) > 2 && kname[:2] == "[]" {
+				kname = strings.Title(kname[2:]) + "s"
+			}
+			typeName := fmt.Sprintf("Sorted%sSet", strings.Title(kname))
+			nodeName := fmt.Sprintf("snode%s", strings.Title(kname))
+
+			cwd, _ := os.Getwd()
+			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
+
+			src := []byte(redblackbstSetSrc)
+			src = bytes.Replace(src, []byte("package redblackbst"), []byte(pkgname), 1)
+
+			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+
+			// need to replace Compare before replacing KType
+			src = replaceRbstCompareFunc(ktype, "RedBlackSet", src)
+			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
+			src = bytes.Replace(src, []byte("RedBlackSet"), []byte(typeName), -1)
+			src = bytes.Replace(src, []byte("snode"), []byte(nodeName), -1)
+
+			fmt.Println(string(src))
+		},
+	}
+}
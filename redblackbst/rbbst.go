@@ -1,5 +1,7 @@
 package redblackbst
 
+import "fmt"
+
 // RedBlack is a sorted map built on a left leaning red black balanced
 // search sorted map. It stores VType values, keyed by KType.
 type RedBlack struct {
@@ -24,6 +26,7 @@ func (r *RedBlack) Clear() { r.root = nil }
 // if the key was already present.
 func (r *RedBlack) Put(k KType, v VType) (old VType, overwrite bool) {
 	r.root, old, overwrite = put(r.root, k, v)
+	r.root.color = black
 	return
 }
 
@@ -254,6 +257,154 @@ func keys(h *node, visit func(KType, VType) bool, lo, hi KType) bool {
 	return true
 }
 
+// Iterator is a cursor over the sorted map that can be paused, composed,
+// or driven by a caller's event loop, unlike the callback-based Keys and
+// RangedKeys. It walks the tree using an explicit stack of ancestor nodes
+// rather than recursion.
+//
+// Next and Prev are both relative to the cursor's current node: Seek,
+// SeekFirst, and SeekLast establish that node, and the first Next after
+// one of them reports that node itself. Every other call to Next or Prev
+// moves the current node one step in the requested direction before
+// reporting it, so the two can be freely interleaved to walk back and
+// forth over the map.
+type Iterator struct {
+	root  *node
+	stack []*node
+	hasLo bool
+	lo    KType
+	hasHi bool
+	hi    KType
+	cur   *node
+}
+
+// Iterator returns a cursor over the whole sorted map, positioned at the
+// smallest key as by SeekFirst.
+func (r RedBlack) Iterator() *Iterator {
+	it := &Iterator{root: r.root}
+	it.SeekFirst()
+	return it
+}
+
+// RangedIterator returns a cursor over the sorted map bounded to keys
+// between lo and hi, positioned at the first key >= lo.
+func (r RedBlack) RangedIterator(lo, hi KType) *Iterator {
+	it := &Iterator{root: r.root, hasLo: true, lo: lo, hasHi: true, hi: hi}
+	it.Seek(lo)
+	return it
+}
+
+// SeekFirst positions the cursor at the smallest key.
+func (it *Iterator) SeekFirst() {
+	it.stack = it.stack[:0]
+	it.pushLeftChain(it.root)
+	it.setCurFromStack()
+}
+
+// SeekLast positions the cursor at the largest key.
+func (it *Iterator) SeekLast() {
+	it.stack = it.stack[:0]
+	it.pushRightChain(it.root)
+	it.setCurFromStack()
+	// Re-seek so Next sees only the largest key on the stack, rather than
+	// popping back up the right spine to smaller keys.
+	if it.cur != nil {
+		it.Seek(it.cur.key)
+	}
+}
+
+// Seek positions the cursor at the smallest key >= k.
+func (it *Iterator) Seek(k KType) {
+	it.stack = it.stack[:0]
+	h := it.root
+	for h != nil {
+		if k.Compare(h.key) <= 0 {
+			it.stack = append(it.stack, h)
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	it.setCurFromStack()
+}
+
+func (it *Iterator) setCurFromStack() {
+	if len(it.stack) == 0 {
+		it.cur = nil
+		return
+	}
+	it.cur = it.stack[len(it.stack)-1]
+}
+
+// Next returns the next key/value pair in ascending order and makes it the
+// cursor's current node. Right after a seek it returns the node the seek
+// established.
+func (it *Iterator) Next() (k KType, v VType, ok bool) {
+	if len(it.stack) == 0 {
+		return
+	}
+	h := it.stack[len(it.stack)-1]
+	if it.hasHi && h.key.Compare(it.hi) > 0 {
+		it.stack = it.stack[:0]
+		return
+	}
+	it.stack = it.stack[:len(it.stack)-1]
+	it.pushLeftChain(h.right)
+	it.cur = h
+	return h.key, h.val, true
+}
+
+// Prev moves the cursor to the predecessor of its current node and
+// returns it.
+func (it *Iterator) Prev() (k KType, v VType, ok bool) {
+	if it.cur == nil {
+		return
+	}
+	pred := predecessor(it.root, it.cur.key)
+	if pred == nil || (it.hasLo && pred.key.Compare(it.lo) < 0) {
+		it.cur = nil
+		it.stack = it.stack[:0]
+		return
+	}
+	// Re-seek to the old current node, pred's successor, so a subsequent
+	// Next rebuilds the ascending-traversal stack and reports that node,
+	// rather than reusing a stack built for the opposite direction.
+	it.Seek(it.cur.key)
+	it.cur = pred
+	return pred.key, pred.val, true
+}
+
+// predecessor finds the node with the largest key less than k, starting
+// the search over from root. It doesn't require k to be present in the
+// tree.
+func predecessor(root *node, k KType) *node {
+	var pred *node
+	h := root
+	for h != nil {
+		if k.Compare(h.key) > 0 {
+			pred = h
+			h = h.right
+		} else {
+			h = h.left
+		}
+	}
+	return pred
+}
+
+func (it *Iterator) pushLeftChain(h *node) {
+	for h != nil {
+		it.stack = append(it.stack, h)
+		h = h.left
+	}
+}
+
+func (it *Iterator) pushRightChain(h *node) {
+	for h != nil {
+		it.stack = append(it.stack, h)
+		h = h.right
+	}
+}
+
 // DeleteMin removes the smallest key and its value from the sorted map.
 func (r *RedBlack) DeleteMin() (oldk KType, oldv VType, ok bool) {
 	r.root, oldk, oldv, ok = deleteMin(r.root)
@@ -454,3 +605,92 @@ func size(x *node) int {
 	}
 	return x.n
 }
+
+// ASCIIArt renders the tree with branch glyphs and per-node coloring
+// (R(key=...) for red, B(key=...) for black), for pasting directly into
+// bug reports.
+func (r RedBlack) ASCIIArt() string {
+	var buf []byte
+	buf = asciiArt(buf, r.root, "", "")
+	return string(buf)
+}
+
+func asciiArt(buf []byte, h *node, prefix, childPrefix string) []byte {
+	if h == nil {
+		return buf
+	}
+	c := "B"
+	if h.color == red {
+		c = "R"
+	}
+	buf = append(buf, prefix...)
+	buf = append(buf, []byte(fmt.Sprintf("%s(key=%v)\n", c, h.key))...)
+	buf = asciiArt(buf, h.left, childPrefix+"├── ", childPrefix+"│   ")
+	buf = asciiArt(buf, h.right, childPrefix+"└── ", childPrefix+"    ")
+	return buf
+}
+
+// check walks the tree and verifies the three LLRB invariants: no
+// right-leaning red links, no two consecutive red links on any path, and
+// equal black-heights to every leaf. It returns a descriptive error naming
+// the offending node, so a buggy Compare on a user's key type is easy to
+// diagnose.
+func (r RedBlack) check() error {
+	if err := checkNoRightLeaningRed(r.root); err != nil {
+		return err
+	}
+	if err := checkNoConsecutiveRed(r.root, false); err != nil {
+		return err
+	}
+	_, err := checkBlackHeight(r.root)
+	return err
+}
+
+func checkNoRightLeaningRed(h *node) error {
+	if h == nil {
+		return nil
+	}
+	if isRed(h.right) {
+		return fmt.Errorf("right-leaning red link at node with key=%v", h.key)
+	}
+	if err := checkNoRightLeaningRed(h.left); err != nil {
+		return err
+	}
+	return checkNoRightLeaningRed(h.right)
+}
+
+func checkNoConsecutiveRed(h *node, parentRed bool) error {
+	if h == nil {
+		return nil
+	}
+	if parentRed && isRed(h) {
+		return fmt.Errorf("two consecutive red links at node with key=%v", h.key)
+	}
+	if err := checkNoConsecutiveRed(h.left, isRed(h)); err != nil {
+		return err
+	}
+	return checkNoConsecutiveRed(h.right, isRed(h))
+}
+
+// checkBlackHeight returns the number of black links on the path from h to
+// any leaf, failing if the left and right subtrees disagree.
+func checkBlackHeight(h *node) (int, error) {
+	if h == nil {
+		return 0, nil
+	}
+	lh, err := checkBlackHeight(h.left)
+	if err != nil {
+		return 0, err
+	}
+	rh, err := checkBlackHeight(h.right)
+	if err != nil {
+		return 0, err
+	}
+	if lh != rh {
+		return 0, fmt.Errorf("unequal black-heights (%d vs %d) at node with key=%v", lh, rh, h.key)
+	}
+	if isRed(h) {
+		return lh, nil
+	}
+	return lh + 1, nil
+}
@@ -0,0 +1,430 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+import "golang.org/x/exp/constraints"
+
+// SortedMap is a generic sorted map built on a left leaning red black
+// balanced search tree, ordered by a pluggable comparator. Construct one
+// with New (custom ordering) or NewOrdered (for constraints.Ordered keys).
+type SortedMap[K, V any] struct {
+	root *gnode[K, V]
+	cmp  func(a, b K) int
+}
+
+// New creates a sorted map ordered by cmp, which must return
+// negative/zero/positive as a is less than, equal to, or greater than b.
+func New[K, V any](cmp func(a, b K) int) *SortedMap[K, V] {
+	return &SortedMap[K, V]{cmp: cmp}
+}
+
+// NewOrdered creates a sorted map for a key type with a natural `<` order.
+func NewOrdered[K constraints.Ordered, V any]() *SortedMap[K, V] {
+	return New[K, V](func(a, b K) int {
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+		return 0
+	})
+}
+
+// IsEmpty tells if the sorted map contains no key/value.
+func (r *SortedMap[K, V]) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the sorted map.
+func (r *SortedMap[K, V]) Size() int { return gsize(r.root) }
+
+// Clear all the values in the sorted map.
+func (r *SortedMap[K, V]) Clear() { r.root = nil }
+
+// Put a value in the sorted map at key k. The old value at k is returned if
+// the key was already present.
+func (r *SortedMap[K, V]) Put(k K, v V) (old V, overwrite bool) {
+	r.root, old, overwrite = gput(r.root, k, v, r.cmp)
+	r.root.color = gblack
+	return
+}
+
+func gput[K, V any](h *gnode[K, V], k K, v V, cmp func(a, b K) int) (_ *gnode[K, V], old V, overwrite bool) {
+	if h == nil {
+		return newGnode(k, v, 1, gred), old, overwrite
+	}
+
+	c := cmp(k, h.key)
+	if c < 0 {
+		h.left, old, overwrite = gput(h.left, k, v, cmp)
+	} else if c > 0 {
+		h.right, old, overwrite = gput(h.right, k, v, cmp)
+	} else {
+		overwrite = true
+		old = h.val
+		h.val = v
+	}
+
+	if isGRed(h.right) && !isGRed(h.left) {
+		h = grotateLeft(h)
+	}
+	if isGRed(h.left) && isGRed(h.left.left) {
+		h = grotateRight(h)
+	}
+	if isGRed(h.left) && isGRed(h.right) {
+		gflipColors(h)
+	}
+	h.n = gsize(h.left) + gsize(h.right) + 1
+	return h, old, overwrite
+}
+
+// Get a value from the sorted map at key k. Returns false if the key
+// doesn't exist.
+func (r *SortedMap[K, V]) Get(k K) (v V, ok bool) {
+	h := r.root
+	for h != nil {
+		c := r.cmp(k, h.key)
+		if c == 0 {
+			return h.val, true
+		} else if c < 0 {
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	return
+}
+
+// Has tells if a value exists at key k. This is short hand for Get.
+func (r *SortedMap[K, V]) Has(k K) bool {
+	_, ok := r.Get(k)
+	return ok
+}
+
+// Min returns the smallest key/value in the sorted map, if it exists.
+func (r *SortedMap[K, V]) Min() (k K, v V, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := gmin(r.root)
+	return h.key, h.val, true
+}
+
+func gmin[K, V any](x *gnode[K, V]) *gnode[K, V] {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+// Max returns the largest key/value in the sorted map, if it exists.
+func (r *SortedMap[K, V]) Max() (k K, v V, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := gmax(r.root)
+	return h.key, h.val, true
+}
+
+func gmax[K, V any](x *gnode[K, V]) *gnode[K, V] {
+	for x.right != nil {
+		x = x.right
+	}
+	return x
+}
+
+// Floor returns the largest key/value in the sorted map whose key is
+// smaller than or equal to k.
+func (r *SortedMap[K, V]) Floor(k K) (rk K, rv V, ok bool) {
+	x := gfloor(r.root, k, r.cmp)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func gfloor[K, V any](h *gnode[K, V], k K, cmp func(a, b K) int) *gnode[K, V] {
+	if h == nil {
+		return nil
+	}
+	c := cmp(k, h.key)
+	if c == 0 {
+		return h
+	}
+	if c < 0 {
+		return gfloor(h.left, k, cmp)
+	}
+	t := gfloor(h.right, k, cmp)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Ceiling returns the smallest key/value in the sorted map whose key is
+// larger than or equal to k.
+func (r *SortedMap[K, V]) Ceiling(k K) (rk K, rv V, ok bool) {
+	x := gceiling(r.root, k, r.cmp)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func gceiling[K, V any](h *gnode[K, V], k K, cmp func(a, b K) int) *gnode[K, V] {
+	if h == nil {
+		return nil
+	}
+	c := cmp(k, h.key)
+	if c == 0 {
+		return h
+	}
+	if c > 0 {
+		return gceiling(h.right, k, cmp)
+	}
+	t := gceiling(h.left, k, cmp)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Select returns the key/value of rank k, meaning the k-th smallest key in
+// the sorted map.
+func (r *SortedMap[K, V]) Select(k int) (rk K, rv V, ok bool) {
+	x := gnodeselect(r.root, k)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func gnodeselect[K, V any](x *gnode[K, V], k int) *gnode[K, V] {
+	if x == nil {
+		return nil
+	}
+	t := gsize(x.left)
+	if t > k {
+		return gnodeselect(x.left, k)
+	} else if t < k {
+		return gnodeselect(x.right, k-t-1)
+	}
+	return x
+}
+
+// Rank is the number of keys less than k.
+func (r *SortedMap[K, V]) Rank(k K) int {
+	return gkeyrank(k, r.root, r.cmp)
+}
+
+func gkeyrank[K, V any](k K, h *gnode[K, V], cmp func(a, b K) int) int {
+	if h == nil {
+		return 0
+	}
+	c := cmp(k, h.key)
+	if c < 0 {
+		return gkeyrank(k, h.left, cmp)
+	} else if c > 0 {
+		return 1 + gsize(h.left) + gkeyrank(k, h.right, cmp)
+	}
+	return gsize(h.left)
+}
+
+// Keys visits every key in the sorted map, in order. It stops when visit
+// returns false.
+func (r *SortedMap[K, V]) Keys(visit func(K, V) bool) {
+	ginorder(r.root, visit)
+}
+
+func ginorder[K, V any](h *gnode[K, V], visit func(K, V) bool) bool {
+	if h == nil {
+		return true
+	}
+	if !ginorder(h.left, visit) {
+		return false
+	}
+	if !visit(h.key, h.val) {
+		return false
+	}
+	return ginorder(h.right, visit)
+}
+
+// RangedKeys visits every key between lo and hi in the sorted map, in
+// order. It stops when visit returns false.
+func (r *SortedMap[K, V]) RangedKeys(lo, hi K, visit func(K, V) bool) {
+	grangedKeys(r.root, visit, lo, hi, r.cmp)
+}
+
+func grangedKeys[K, V any](h *gnode[K, V], visit func(K, V) bool, lo, hi K, cmp func(a, b K) int) bool {
+	if h == nil {
+		return true
+	}
+	cmplo := cmp(lo, h.key)
+	cmphi := cmp(hi, h.key)
+	if cmplo < 0 {
+		if !grangedKeys(h.left, visit, lo, hi, cmp) {
+			return false
+		}
+	}
+	if cmplo <= 0 && cmphi >= 0 {
+		if !visit(h.key, h.val) {
+			return false
+		}
+	}
+	if cmphi > 0 {
+		if !grangedKeys(h.right, visit, lo, hi, cmp) {
+			return false
+		}
+	}
+	return true
+}
+
+// Delete key k from the sorted map, if it exists.
+func (r *SortedMap[K, V]) Delete(k K) (old V, ok bool) {
+	if r.root == nil {
+		return
+	}
+	r.root, old, ok = gdelete(r.root, k, r.cmp)
+	if r.root != nil {
+		r.root.color = gblack
+	}
+	return
+}
+
+func gdelete[K, V any](h *gnode[K, V], k K, cmp func(a, b K) int) (_ *gnode[K, V], old V, ok bool) {
+	if h == nil {
+		return h, old, false
+	}
+
+	if cmp(k, h.key) < 0 {
+		if h.left == nil {
+			return h, old, false
+		}
+		if !isGRed(h.left) && !isGRed(h.left.left) {
+			h = gmoveRedLeft(h)
+		}
+		h.left, old, ok = gdelete(h.left, k, cmp)
+		return gbalance(h), old, ok
+	}
+
+	if isGRed(h.left) {
+		h = grotateRight(h)
+	}
+	if cmp(k, h.key) == 0 && h.right == nil {
+		return nil, h.val, true
+	}
+	if h.right != nil && !isGRed(h.right) && !isGRed(h.right.left) {
+		h = gmoveRedRight(h)
+	}
+	if cmp(k, h.key) == 0 {
+		sub := gmin(h.right)
+		old, h.key, h.val = h.val, sub.key, sub.val
+		h.right = gdeleteMin(h.right)
+		ok = true
+	} else {
+		h.right, old, ok = gdelete(h.right, k, cmp)
+	}
+	return gbalance(h), old, ok
+}
+
+func gdeleteMin[K, V any](h *gnode[K, V]) *gnode[K, V] {
+	if h.left == nil {
+		return nil
+	}
+	if !isGRed(h.left) && !isGRed(h.left.left) {
+		h = gmoveRedLeft(h)
+	}
+	h.left = gdeleteMin(h.left)
+	return gbalance(h)
+}
+
+// deletions
+
+func gmoveRedLeft[K, V any](h *gnode[K, V]) *gnode[K, V] {
+	gflipColors(h)
+	if isGRed(h.right.left) {
+		h.right = grotateRight(h.right)
+		h = grotateLeft(h)
+		gflipColors(h)
+	}
+	return h
+}
+
+func gmoveRedRight[K, V any](h *gnode[K, V]) *gnode[K, V] {
+	gflipColors(h)
+	if isGRed(h.left.left) {
+		h = grotateRight(h)
+		gflipColors(h)
+	}
+	return h
+}
+
+func gbalance[K, V any](h *gnode[K, V]) *gnode[K, V] {
+	if isGRed(h.right) {
+		h = grotateLeft(h)
+	}
+	if isGRed(h.left) && isGRed(h.left.left) {
+		h = grotateRight(h)
+	}
+	if isGRed(h.left) && isGRed(h.right) {
+		gflipColors(h)
+	}
+	h.n = gsize(h.left) + gsize(h.right) + 1
+	return h
+}
+
+// nodes
+
+const (
+	gred   = true
+	gblack = false
+)
+
+type gnode[K, V any] struct {
+	key         K
+	val         V
+	left, right *gnode[K, V]
+	n           int
+	color       bool
+}
+
+func newGnode[K, V any](k K, v V, n int, color bool) *gnode[K, V] {
+	return &gnode[K, V]{key: k, val: v, n: n, color: color}
+}
+
+func isGRed[K, V any](x *gnode[K, V]) bool { return (x != nil) && (x.color == gred) }
+
+func grotateLeft[K, V any](h *gnode[K, V]) *gnode[K, V] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = gred
+	x.n = h.n
+	h.n = 1 + gsize(h.left) + gsize(h.right)
+	return x
+}
+
+func grotateRight[K, V any](h *gnode[K, V]) *gnode[K, V] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = gred
+	x.n = h.n
+	h.n = 1 + gsize(h.left) + gsize(h.right)
+	return x
+}
+
+func gflipColors[K, V any](h *gnode[K, V]) {
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+func gsize[K, V any](x *gnode[K, V]) int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
@@ -0,0 +1,334 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+import "golang.org/x/exp/constraints"
+
+// IntervalTree is a generic augmented interval tree built on a left
+// leaning red black balanced search tree, ordered by a pluggable
+// comparator over the key type. Each node tracks the maximum endpoint in
+// its subtree. V is the interval payload and must implement Min() K and
+// Max() K.
+type IntervalTree[K any, V interface {
+	Min() K
+	Max() K
+}] struct {
+	root *ginode[K, V]
+	cmp  func(a, b K) int
+}
+
+// NewIntervalTree creates an interval tree ordered by cmp, which must
+// return negative/zero/positive as a is less than, equal to, or greater
+// than b.
+func NewIntervalTree[K any, V interface {
+	Min() K
+	Max() K
+}](cmp func(a, b K) int) *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{cmp: cmp}
+}
+
+// NewOrderedIntervalTree creates an interval tree for a key type with a
+// natural `<` order.
+func NewOrderedIntervalTree[K constraints.Ordered, V interface {
+	Min() K
+	Max() K
+}]() *IntervalTree[K, V] {
+	return NewIntervalTree[K, V](func(a, b K) int {
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+		return 0
+	})
+}
+
+// IsEmpty tells if the interval tree contains no intervals.
+func (r *IntervalTree[K, V]) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the interval tree.
+func (r *IntervalTree[K, V]) Size() int { return ginsize(r.root) }
+
+// Insert a value into the interval tree, keyed by v.Min().
+func (r *IntervalTree[K, V]) Insert(v V) {
+	r.root = gininsert(r.root, v, r.cmp)
+	r.root.color = ginblack
+}
+
+func gininsert[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], v V, cmp func(a, b K) int) *ginode[K, V] {
+	if h == nil {
+		return newGinode[K, V](v, 1, ginred)
+	}
+
+	c := cmp(v.Min(), h.val.Min())
+	if c < 0 {
+		h.left = gininsert(h.left, v, cmp)
+	} else if c > 0 {
+		h.right = gininsert(h.right, v, cmp)
+	} else {
+		h.val = v
+	}
+
+	if isGInRed(h.right) && !isGInRed(h.left) {
+		h = ginrotateLeft(h, cmp)
+	}
+	if isGInRed(h.left) && isGInRed(h.left.left) {
+		h = ginrotateRight(h, cmp)
+	}
+	if isGInRed(h.left) && isGInRed(h.right) {
+		ginflipColors(h)
+	}
+	h.n = ginsize(h.left) + ginsize(h.right) + 1
+	h.max = ginmax3(h.val.Max(), h.left, h.right, cmp)
+	return h
+}
+
+// Delete removes the interval keyed by v.Min() from the tree, if it
+// exists.
+func (r *IntervalTree[K, V]) Delete(v V) {
+	if r.root == nil {
+		return
+	}
+	r.root = gindelete(r.root, v.Min(), r.cmp)
+	if r.root != nil {
+		r.root.color = ginblack
+	}
+}
+
+func gindelete[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], k K, cmp func(a, b K) int) *ginode[K, V] {
+	if h == nil {
+		return nil
+	}
+
+	if cmp(k, h.val.Min()) < 0 {
+		if h.left == nil {
+			return h
+		}
+		if !isGInRed(h.left) && !isGInRed(h.left.left) {
+			h = ginmoveRedLeft(h, cmp)
+		}
+		h.left = gindelete(h.left, k, cmp)
+		return ginbalance(h, cmp)
+	}
+
+	if isGInRed(h.left) {
+		h = ginrotateRight(h, cmp)
+	}
+	if cmp(k, h.val.Min()) == 0 && h.right == nil {
+		return nil
+	}
+	if h.right != nil && !isGInRed(h.right) && !isGInRed(h.right.left) {
+		h = ginmoveRedRight(h, cmp)
+	}
+	if cmp(k, h.val.Min()) == 0 {
+		h.val = ginmin(h.right).val
+		h.right = gindeleteMin(h.right, cmp)
+	} else {
+		h.right = gindelete(h.right, k, cmp)
+	}
+	return ginbalance(h, cmp)
+}
+
+func gindeleteMin[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(a, b K) int) *ginode[K, V] {
+	if h.left == nil {
+		return nil
+	}
+	if !isGInRed(h.left) && !isGInRed(h.left.left) {
+		h = ginmoveRedLeft(h, cmp)
+	}
+	h.left = gindeleteMin(h.left, cmp)
+	return ginbalance(h, cmp)
+}
+
+func ginmin[K any, V interface {
+	Min() K
+	Max() K
+}](x *ginode[K, V]) *ginode[K, V] {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+func ginmoveRedLeft[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(a, b K) int) *ginode[K, V] {
+	ginflipColors(h)
+	if isGInRed(h.right.left) {
+		h.right = ginrotateRight(h.right, cmp)
+		h = ginrotateLeft(h, cmp)
+		ginflipColors(h)
+	}
+	return h
+}
+
+func ginmoveRedRight[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(a, b K) int) *ginode[K, V] {
+	ginflipColors(h)
+	if isGInRed(h.left.left) {
+		h = ginrotateRight(h, cmp)
+		ginflipColors(h)
+	}
+	return h
+}
+
+func ginbalance[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(a, b K) int) *ginode[K, V] {
+	if isGInRed(h.right) {
+		h = ginrotateLeft(h, cmp)
+	}
+	if isGInRed(h.left) && isGInRed(h.left.left) {
+		h = ginrotateRight(h, cmp)
+	}
+	if isGInRed(h.left) && isGInRed(h.right) {
+		ginflipColors(h)
+	}
+	h.n = ginsize(h.left) + ginsize(h.right) + 1
+	h.max = ginmax3(h.val.Max(), h.left, h.right, cmp)
+	return h
+}
+
+// SearchAll visits every interval whose range overlaps the query described
+// by cmp, which should return negative/zero/positive to indicate whether a
+// given point lies left of, inside, or right of the queried range.
+// Subtrees whose augmented max endpoint falls left of the queried range
+// are pruned.
+func (r *IntervalTree[K, V]) SearchAll(cmp func(K) int) []V {
+	var out []V
+	ginsearch(r.root, cmp, r.cmp, &out)
+	return out
+}
+
+func ginsearch[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(K) int, kcmp func(a, b K) int, out *[]V) {
+	if h == nil || cmp(h.max) < 0 {
+		return
+	}
+	if h.left != nil && cmp(h.left.max) >= 0 {
+		ginsearch(h.left, cmp, kcmp, out)
+	}
+	if cmp(h.val.Min()) <= 0 && cmp(h.val.Max()) >= 0 {
+		*out = append(*out, h.val)
+	}
+	if cmp(h.val.Min()) <= 0 {
+		ginsearch(h.right, cmp, kcmp, out)
+	}
+}
+
+// nodes
+
+const (
+	ginred   = true
+	ginblack = false
+)
+
+type ginode[K any, V interface {
+	Min() K
+	Max() K
+}] struct {
+	val         V
+	left, right *ginode[K, V]
+	max         K
+	n           int
+	color       bool
+}
+
+func newGinode[K any, V interface {
+	Min() K
+	Max() K
+}](v V, n int, color bool) *ginode[K, V] {
+	h := &ginode[K, V]{val: v, n: n, color: color}
+	h.max = v.Max()
+	return h
+}
+
+func isGInRed[K any, V interface {
+	Min() K
+	Max() K
+}](x *ginode[K, V]) bool {
+	return (x != nil) && (x.color == ginred)
+}
+
+func ginrotateLeft[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(a, b K) int) *ginode[K, V] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = ginred
+	x.n = h.n
+	h.n = 1 + ginsize(h.left) + ginsize(h.right)
+	h.max = ginmax3(h.val.Max(), h.left, h.right, cmp)
+	x.max = ginmax3(x.val.Max(), x.left, x.right, cmp)
+	return x
+}
+
+func ginrotateRight[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V], cmp func(a, b K) int) *ginode[K, V] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = ginred
+	x.n = h.n
+	h.n = 1 + ginsize(h.left) + ginsize(h.right)
+	h.max = ginmax3(h.val.Max(), h.left, h.right, cmp)
+	x.max = ginmax3(x.val.Max(), x.left, x.right, cmp)
+	return x
+}
+
+func ginflipColors[K any, V interface {
+	Min() K
+	Max() K
+}](h *ginode[K, V]) {
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+func ginsize[K any, V interface {
+	Min() K
+	Max() K
+}](x *ginode[K, V]) int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
+
+// ginmax3 returns the largest of a node's own Max endpoint and its two
+// children's augmented max endpoints.
+func ginmax3[K any, V interface {
+	Min() K
+	Max() K
+}](m K, left, right *ginode[K, V], cmp func(a, b K) int) K {
+	if left != nil && cmp(left.max, m) > 0 {
+		m = left.max
+	}
+	if right != nil && cmp(right.max, m) > 0 {
+		m = right.max
+	}
+	return m
+}
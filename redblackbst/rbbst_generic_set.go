@@ -0,0 +1,349 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+import "golang.org/x/exp/constraints"
+
+// SortedSet is a generic sorted set built on a left leaning red black
+// balanced search tree, ordered by a pluggable comparator. Construct one
+// with NewSet (custom ordering) or NewOrderedSet (for constraints.Ordered
+// elements).
+type SortedSet[K any] struct {
+	root *gsnode[K]
+	cmp  func(a, b K) int
+}
+
+// NewSet creates a sorted set ordered by cmp, which must return
+// negative/zero/positive as a is less than, equal to, or greater than b.
+func NewSet[K any](cmp func(a, b K) int) *SortedSet[K] {
+	return &SortedSet[K]{cmp: cmp}
+}
+
+// NewOrderedSet creates a sorted set for an element type with a natural
+// `<` order.
+func NewOrderedSet[K constraints.Ordered]() *SortedSet[K] {
+	return NewSet[K](func(a, b K) int {
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+		return 0
+	})
+}
+
+// IsEmpty tells if the sorted set contains no elements.
+func (r *SortedSet[K]) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the sorted set.
+func (r *SortedSet[K]) Size() int { return gssize(r.root) }
+
+// Add a key to the sorted set. Returns false if the key was already
+// present.
+func (r *SortedSet[K]) Add(k K) (added bool) {
+	var existed bool
+	r.root, existed = gsput(r.root, k, r.cmp)
+	r.root.color = gsblack
+	return !existed
+}
+
+func gsput[K any](h *gsnode[K], k K, cmp func(a, b K) int) (_ *gsnode[K], existed bool) {
+	if h == nil {
+		return newGsnode(k, 1, gsred), false
+	}
+
+	c := cmp(k, h.key)
+	if c < 0 {
+		h.left, existed = gsput(h.left, k, cmp)
+	} else if c > 0 {
+		h.right, existed = gsput(h.right, k, cmp)
+	} else {
+		existed = true
+	}
+
+	if isGSRed(h.right) && !isGSRed(h.left) {
+		h = gsrotateLeft(h)
+	}
+	if isGSRed(h.left) && isGSRed(h.left.left) {
+		h = gsrotateRight(h)
+	}
+	if isGSRed(h.left) && isGSRed(h.right) {
+		gsflipColors(h)
+	}
+	h.n = gssize(h.left) + gssize(h.right) + 1
+	return h, existed
+}
+
+// Has tells if k is a member of the sorted set.
+func (r *SortedSet[K]) Has(k K) bool {
+	h := r.root
+	for h != nil {
+		c := r.cmp(k, h.key)
+		if c == 0 {
+			return true
+		} else if c < 0 {
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	return false
+}
+
+// Delete removes k from the sorted set, if it exists.
+func (r *SortedSet[K]) Delete(k K) (ok bool) {
+	if r.root == nil {
+		return
+	}
+	r.root, ok = gsdelete(r.root, k, r.cmp)
+	if r.root != nil {
+		r.root.color = gsblack
+	}
+	return
+}
+
+func gsdelete[K any](h *gsnode[K], k K, cmp func(a, b K) int) (_ *gsnode[K], ok bool) {
+	if h == nil {
+		return h, false
+	}
+
+	if cmp(k, h.key) < 0 {
+		if h.left == nil {
+			return h, false
+		}
+		if !isGSRed(h.left) && !isGSRed(h.left.left) {
+			h = gsmoveRedLeft(h)
+		}
+		h.left, ok = gsdelete(h.left, k, cmp)
+		return gsbalance(h), ok
+	}
+
+	if isGSRed(h.left) {
+		h = gsrotateRight(h)
+	}
+	if cmp(k, h.key) == 0 && h.right == nil {
+		return nil, true
+	}
+	if h.right != nil && !isGSRed(h.right) && !isGSRed(h.right.left) {
+		h = gsmoveRedRight(h)
+	}
+	if cmp(k, h.key) == 0 {
+		h.key = gsmin(h.right).key
+		h.right = gsdeleteMin(h.right)
+		ok = true
+	} else {
+		h.right, ok = gsdelete(h.right, k, cmp)
+	}
+	return gsbalance(h), ok
+}
+
+func gsdeleteMin[K any](h *gsnode[K]) *gsnode[K] {
+	if h.left == nil {
+		return nil
+	}
+	if !isGSRed(h.left) && !isGSRed(h.left.left) {
+		h = gsmoveRedLeft(h)
+	}
+	h.left = gsdeleteMin(h.left)
+	return gsbalance(h)
+}
+
+func gsmin[K any](x *gsnode[K]) *gsnode[K] {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+// Range visits every key between lo and hi in the sorted set, in order. It
+// stops when visit returns false.
+func (r *SortedSet[K]) Range(lo, hi K, visit func(K) bool) {
+	gsrange(r.root, visit, lo, hi, r.cmp)
+}
+
+func gsrange[K any](h *gsnode[K], visit func(K) bool, lo, hi K, cmp func(a, b K) int) bool {
+	if h == nil {
+		return true
+	}
+	cmplo := cmp(lo, h.key)
+	cmphi := cmp(hi, h.key)
+	if cmplo < 0 {
+		if !gsrange(h.left, visit, lo, hi, cmp) {
+			return false
+		}
+	}
+	if cmplo <= 0 && cmphi >= 0 {
+		if !visit(h.key) {
+			return false
+		}
+	}
+	if cmphi > 0 {
+		if !gsrange(h.right, visit, lo, hi, cmp) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set containing every key in r or other.
+func (r *SortedSet[K]) Union(other *SortedSet[K]) *SortedSet[K] {
+	out := NewSet[K](r.cmp)
+	gsinorder(r.root, func(k K) bool { out.Add(k); return true })
+	if other != nil {
+		gsinorder(other.root, func(k K) bool { out.Add(k); return true })
+	}
+	return out
+}
+
+// Intersection returns a new set containing every key present in both r
+// and other.
+func (r *SortedSet[K]) Intersection(other *SortedSet[K]) *SortedSet[K] {
+	out := NewSet[K](r.cmp)
+	if other == nil {
+		return out
+	}
+	gsinorder(r.root, func(k K) bool {
+		if other.Has(k) {
+			out.Add(k)
+		}
+		return true
+	})
+	return out
+}
+
+// Difference returns a new set containing every key present in r but not
+// in other.
+func (r *SortedSet[K]) Difference(other *SortedSet[K]) *SortedSet[K] {
+	out := NewSet[K](r.cmp)
+	gsinorder(r.root, func(k K) bool {
+		if other == nil || !other.Has(k) {
+			out.Add(k)
+		}
+		return true
+	})
+	return out
+}
+
+// SubsetOf tells if every key in r is also present in other.
+func (r *SortedSet[K]) SubsetOf(other *SortedSet[K]) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+	subset := true
+	gsinorder(r.root, func(k K) bool {
+		if !other.Has(k) {
+			subset = false
+			return false
+		}
+		return true
+	})
+	return subset
+}
+
+func gsinorder[K any](h *gsnode[K], visit func(K) bool) bool {
+	if h == nil {
+		return true
+	}
+	if !gsinorder(h.left, visit) {
+		return false
+	}
+	if !visit(h.key) {
+		return false
+	}
+	return gsinorder(h.right, visit)
+}
+
+// deletions
+
+func gsmoveRedLeft[K any](h *gsnode[K]) *gsnode[K] {
+	gsflipColors(h)
+	if isGSRed(h.right.left) {
+		h.right = gsrotateRight(h.right)
+		h = gsrotateLeft(h)
+		gsflipColors(h)
+	}
+	return h
+}
+
+func gsmoveRedRight[K any](h *gsnode[K]) *gsnode[K] {
+	gsflipColors(h)
+	if isGSRed(h.left.left) {
+		h = gsrotateRight(h)
+		gsflipColors(h)
+	}
+	return h
+}
+
+func gsbalance[K any](h *gsnode[K]) *gsnode[K] {
+	if isGSRed(h.right) {
+		h = gsrotateLeft(h)
+	}
+	if isGSRed(h.left) && isGSRed(h.left.left) {
+		h = gsrotateRight(h)
+	}
+	if isGSRed(h.left) && isGSRed(h.right) {
+		gsflipColors(h)
+	}
+	h.n = gssize(h.left) + gssize(h.right) + 1
+	return h
+}
+
+// nodes
+
+const (
+	gsred   = true
+	gsblack = false
+)
+
+type gsnode[K any] struct {
+	key         K
+	left, right *gsnode[K]
+	n           int
+	color       bool
+}
+
+func newGsnode[K any](k K, n int, color bool) *gsnode[K] {
+	return &gsnode[K]{key: k, n: n, color: color}
+}
+
+func isGSRed[K any](x *gsnode[K]) bool { return (x != nil) && (x.color == gsred) }
+
+func gsrotateLeft[K any](h *gsnode[K]) *gsnode[K] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = gsred
+	x.n = h.n
+	h.n = 1 + gssize(h.left) + gssize(h.right)
+	return x
+}
+
+func gsrotateRight[K any](h *gsnode[K]) *gsnode[K] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = gsred
+	x.n = h.n
+	h.n = 1 + gssize(h.left) + gssize(h.right)
+	return x
+}
+
+func gsflipColors[K any](h *gsnode[K]) {
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+func gssize[K any](x *gsnode[K]) int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
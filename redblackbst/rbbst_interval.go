@@ -0,0 +1,295 @@
+package redblackbst
+
+import "fmt"
+
+// GENERATED CODE!!!
+
+// compare orders two keys. It is the single indirection point rewritten by
+// the generator for primitive key types, so every comparison in this file
+// must go through it rather than calling KType.Compare directly.
+func (r IntervalTree) compare(a, b KType) int { return a.Compare(b) }
+
+// IntervalTree is an augmented interval tree built on a left leaning red
+// black balanced search tree. It stores VType intervals, ordered by KType.
+//
+// KType must implement:
+//
+//	Compare(other KType) int
+//
+// VType is the interval payload and must implement:
+//
+//	Min() KType
+//	Max() KType
+type IntervalTree struct {
+	root *inode
+}
+
+// NewIntervalTree creates an empty interval tree.
+func NewIntervalTree() *IntervalTree { return &IntervalTree{} }
+
+// IsEmpty tells if the interval tree contains no intervals.
+func (r IntervalTree) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the interval tree.
+func (r IntervalTree) Size() int { return isize(r.root) }
+
+// Insert a value into the interval tree, keyed by v.Min().
+func (r *IntervalTree) Insert(v VType) {
+	r.root = iinsert(r.root, v, r.compare)
+	r.root.color = iblack
+}
+
+func iinsert(h *inode, v VType, cmp func(a, b KType) int) *inode {
+	if h == nil {
+		return newInode(v, 1, ired)
+	}
+
+	c := cmp(v.Min(), h.val.Min())
+	if c < 0 {
+		h.left = iinsert(h.left, v, cmp)
+	} else if c > 0 {
+		h.right = iinsert(h.right, v, cmp)
+	} else {
+		h.val = v
+	}
+
+	if isIRed(h.right) && !isIRed(h.left) {
+		h = irotateLeft(h, cmp)
+	}
+	if isIRed(h.left) && isIRed(h.left.left) {
+		h = irotateRight(h, cmp)
+	}
+	if isIRed(h.left) && isIRed(h.right) {
+		iflipColors(h)
+	}
+	h.n = isize(h.left) + isize(h.right) + 1
+	h.max = imax3(h.val.Max(), h.left, h.right, cmp)
+	return h
+}
+
+// Delete removes the interval keyed by v.Min() from the tree, if it exists.
+func (r *IntervalTree) Delete(v VType) {
+	if r.root == nil {
+		return
+	}
+	r.root = idelete(r.root, v.Min(), r.compare)
+	if !r.IsEmpty() {
+		r.root.color = iblack
+	}
+}
+
+func idelete(h *inode, k KType, cmp func(a, b KType) int) *inode {
+	if h == nil {
+		return nil
+	}
+
+	if cmp(k, h.val.Min()) < 0 {
+		if h.left == nil {
+			return h
+		}
+		if !isIRed(h.left) && !isIRed(h.left.left) {
+			h = imoveRedLeft(h, cmp)
+		}
+		h.left = idelete(h.left, k, cmp)
+		return ibalance(h, cmp)
+	}
+
+	if isIRed(h.left) {
+		h = irotateRight(h, cmp)
+	}
+
+	if cmp(k, h.val.Min()) == 0 && h.right == nil {
+		return nil
+	}
+
+	if h.right != nil && !isIRed(h.right) && !isIRed(h.right.left) {
+		h = imoveRedRight(h, cmp)
+	}
+
+	if cmp(k, h.val.Min()) == 0 {
+		h.val = imin(h.right).val
+		h.right = ideleteMin(h.right, cmp)
+	} else {
+		h.right = idelete(h.right, k, cmp)
+	}
+
+	return ibalance(h, cmp)
+}
+
+func ideleteMin(h *inode, cmp func(a, b KType) int) *inode {
+	if h.left == nil {
+		return nil
+	}
+	if !isIRed(h.left) && !isIRed(h.left.left) {
+		h = imoveRedLeft(h, cmp)
+	}
+	h.left = ideleteMin(h.left, cmp)
+	return ibalance(h, cmp)
+}
+
+func imin(x *inode) *inode {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+// SearchAll visits every interval whose range overlaps the query described
+// by cmp, which should return negative/zero/positive to indicate whether a
+// given point lies left of, inside, or right of the queried range. Subtrees
+// whose augmented max endpoint falls left of the queried range are pruned.
+func (r IntervalTree) SearchAll(cmp func(KType) int) []VType {
+	var out []VType
+	isearch(r.root, cmp, &out)
+	return out
+}
+
+func isearch(h *inode, cmp func(KType) int, out *[]VType) {
+	if h == nil || cmp(h.max) < 0 {
+		return
+	}
+	if h.left != nil && cmp(h.left.max) >= 0 {
+		isearch(h.left, cmp, out)
+	}
+	if cmp(h.val.Min()) <= 0 && cmp(h.val.Max()) >= 0 {
+		*out = append(*out, h.val)
+	}
+	if cmp(h.val.Min()) <= 0 {
+		isearch(h.right, cmp, out)
+	}
+}
+
+// ASCIIArt renders the tree with branch glyphs and per-node coloring, for
+// pasting directly into bug reports.
+func (r IntervalTree) ASCIIArt() string {
+	var buf []byte
+	buf = iasciiArt(buf, r.root, "", "")
+	return string(buf)
+}
+
+func iasciiArt(buf []byte, h *inode, prefix, childPrefix string) []byte {
+	if h == nil {
+		return buf
+	}
+	c := "B"
+	if h.color == ired {
+		c = "R"
+	}
+	buf = append(buf, prefix...)
+	buf = append(buf, []byte(fmt.Sprintf("%s(key=%v)\n", c, h.val.Min()))...)
+	buf = iasciiArt(buf, h.left, childPrefix+"├── ", childPrefix+"│   ")
+	buf = iasciiArt(buf, h.right, childPrefix+"└── ", childPrefix+"    ")
+	return buf
+}
+
+// deletions
+
+func imoveRedLeft(h *inode, cmp func(a, b KType) int) *inode {
+	iflipColors(h)
+	if isIRed(h.right.left) {
+		h.right = irotateRight(h.right, cmp)
+		h = irotateLeft(h, cmp)
+		iflipColors(h)
+	}
+	return h
+}
+
+func imoveRedRight(h *inode, cmp func(a, b KType) int) *inode {
+	iflipColors(h)
+	if isIRed(h.left.left) {
+		h = irotateRight(h, cmp)
+		iflipColors(h)
+	}
+	return h
+}
+
+func ibalance(h *inode, cmp func(a, b KType) int) *inode {
+	if isIRed(h.right) {
+		h = irotateLeft(h, cmp)
+	}
+	if isIRed(h.left) && isIRed(h.left.left) {
+		h = irotateRight(h, cmp)
+	}
+	if isIRed(h.left) && isIRed(h.right) {
+		iflipColors(h)
+	}
+	h.n = isize(h.left) + isize(h.right) + 1
+	h.max = imax3(h.val.Max(), h.left, h.right, cmp)
+	return h
+}
+
+// nodes
+
+const (
+	ired   = true
+	iblack = false
+)
+
+type inode struct {
+	val         VType
+	left, right *inode
+	max         KType
+	n           int
+	color       bool
+}
+
+func newInode(v VType, n int, color bool) *inode {
+	h := &inode{val: v, n: n, color: color}
+	h.max = v.Max()
+	return h
+}
+
+func isIRed(x *inode) bool { return (x != nil) && (x.color == ired) }
+
+func irotateLeft(h *inode, cmp func(a, b KType) int) *inode {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = ired
+	x.n = h.n
+	h.n = 1 + isize(h.left) + isize(h.right)
+	h.max = imax3(h.val.Max(), h.left, h.right, cmp)
+	x.max = imax3(x.val.Max(), x.left, x.right, cmp)
+	return x
+}
+
+func irotateRight(h *inode, cmp func(a, b KType) int) *inode {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = ired
+	x.n = h.n
+	h.n = 1 + isize(h.left) + isize(h.right)
+	h.max = imax3(h.val.Max(), h.left, h.right, cmp)
+	x.max = imax3(x.val.Max(), x.left, x.right, cmp)
+	return x
+}
+
+func iflipColors(h *inode) {
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+func isize(x *inode) int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
+
+// imax3 returns the largest of a node's own Max endpoint and its two
+// children's augmented max endpoints.
+func imax3(m KType, left, right *inode, cmp func(a, b KType) int) KType {
+	if left != nil && cmp(left.max, m) > 0 {
+		m = left.max
+	}
+	if right != nil && cmp(right.max, m) > 0 {
+		m = right.max
+	}
+	return m
+}
@@ -0,0 +1,392 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+// compare orders two keys. It is the single indirection point rewritten by
+// the generator for primitive key types, so every comparison in this file
+// must go through it rather than calling KType.Compare directly.
+func (r PersistentMap) compare(a, b KType) int { return a.Compare(b) }
+
+// PersistentMap is an immutable, persistent sorted map built on a left
+// leaning red black balanced search tree. Every Set/Delete returns a new
+// *PersistentMap sharing unmodified subtrees with the old one; existing
+// references keep seeing their original tree.
+type PersistentMap struct {
+	root *pnode
+}
+
+// NewPersistentMap creates an empty persistent map.
+func NewPersistentMap() *PersistentMap { return &PersistentMap{} }
+
+// IsEmpty tells if the persistent map contains no key/value.
+func (r PersistentMap) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Len is the number of key/value pairs in the persistent map.
+func (r PersistentMap) Len() int { return psize(r.root) }
+
+// Get a value from the persistent map at key k. Returns false if the key
+// doesn't exist.
+func (r PersistentMap) Get(k KType) (v VType, ok bool) {
+	h := r.root
+	for h != nil {
+		c := r.compare(k, h.key)
+		if c == 0 {
+			return h.val, true
+		} else if c < 0 {
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	return
+}
+
+// Set returns a new persistent map with v stored at key k, sharing every
+// subtree untouched by the update with r.
+func (r PersistentMap) Set(k KType, v VType) *PersistentMap {
+	root := pput(r.root, k, v, nil, r.compare)
+	root.color = pblack
+	return &PersistentMap{root: root}
+}
+
+func pput(h *pnode, k KType, v VType, owner *Builder, cmp func(a, b KType) int) *pnode {
+	if h == nil {
+		return newPnode(k, v, 1, pred, owner)
+	}
+	h = pclone(h, owner)
+
+	c := cmp(k, h.key)
+	if c < 0 {
+		h.left = pput(h.left, k, v, owner, cmp)
+	} else if c > 0 {
+		h.right = pput(h.right, k, v, owner, cmp)
+	} else {
+		h.val = v
+	}
+
+	if isPRed(h.right) && !isPRed(h.left) {
+		h = protateLeft(h, owner)
+	}
+	if isPRed(h.left) && isPRed(h.left.left) {
+		h = protateRight(h, owner)
+	}
+	if isPRed(h.left) && isPRed(h.right) {
+		pflipColors(h, owner)
+	}
+	h.n = psize(h.left) + psize(h.right) + 1
+	return h
+}
+
+// Delete returns a new persistent map without key k, sharing every subtree
+// untouched by the removal with r.
+func (r PersistentMap) Delete(k KType) *PersistentMap {
+	if r.root == nil {
+		return &r
+	}
+	root := pdelete(r.root, k, nil, r.compare)
+	if root != nil {
+		root.color = pblack
+	}
+	return &PersistentMap{root: root}
+}
+
+func pdelete(h *pnode, k KType, owner *Builder, cmp func(a, b KType) int) *pnode {
+	if h == nil {
+		return nil
+	}
+	h = pclone(h, owner)
+
+	if cmp(k, h.key) < 0 {
+		if h.left == nil {
+			return h
+		}
+		if !isPRed(h.left) && !isPRed(h.left.left) {
+			h = pmoveRedLeft(h, owner)
+		}
+		h.left = pdelete(h.left, k, owner, cmp)
+		return pbalance(h, owner)
+	}
+
+	if isPRed(h.left) {
+		h = protateRight(h, owner)
+	}
+	if cmp(k, h.key) == 0 && h.right == nil {
+		return nil
+	}
+	if h.right != nil && !isPRed(h.right) && !isPRed(h.right.left) {
+		h = pmoveRedRight(h, owner)
+	}
+	if cmp(k, h.key) == 0 {
+		sub := pmin(h.right)
+		h.key, h.val = sub.key, sub.val
+		h.right = pdeleteMin(h.right, owner)
+	} else {
+		h.right = pdelete(h.right, k, owner, cmp)
+	}
+	return pbalance(h, owner)
+}
+
+func pdeleteMin(h *pnode, owner *Builder) *pnode {
+	if h.left == nil {
+		return nil
+	}
+	h = pclone(h, owner)
+	if !isPRed(h.left) && !isPRed(h.left.left) {
+		h = pmoveRedLeft(h, owner)
+	}
+	h.left = pdeleteMin(h.left, owner)
+	return pbalance(h, owner)
+}
+
+func pmin(x *pnode) *pnode {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+// Iterator walks a persistent map in key order without recursion, using an
+// explicit stack of ancestor nodes.
+type Iterator struct {
+	root  *pnode
+	stack []*pnode
+	cmp   func(a, b KType) int
+}
+
+// Iterator returns a cursor positioned before the first key.
+func (r PersistentMap) Iterator() *Iterator {
+	it := &Iterator{root: r.root, cmp: r.compare}
+	it.SeekFirst()
+	return it
+}
+
+// SeekFirst positions the cursor at the smallest key.
+func (it *Iterator) SeekFirst() {
+	it.stack = it.stack[:0]
+	it.pushLeftChain(it.root)
+}
+
+// SeekLast positions the cursor at the largest key.
+func (it *Iterator) SeekLast() {
+	it.stack = it.stack[:0]
+	it.pushRightChain(it.root)
+}
+
+// Seek positions the cursor at the smallest key >= k.
+func (it *Iterator) Seek(k KType) {
+	it.stack = it.stack[:0]
+	h := it.root
+	for h != nil {
+		if it.cmp(k, h.key) <= 0 {
+			it.stack = append(it.stack, h)
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+}
+
+// Next returns the next key/value pair in ascending order.
+func (it *Iterator) Next() (k KType, v VType, ok bool) {
+	if len(it.stack) == 0 {
+		return
+	}
+	h := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.pushLeftChain(h.right)
+	return h.key, h.val, true
+}
+
+// Prev returns the next key/value pair in descending order.
+func (it *Iterator) Prev() (k KType, v VType, ok bool) {
+	if len(it.stack) == 0 {
+		return
+	}
+	h := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.pushRightChain(h.left)
+	return h.key, h.val, true
+}
+
+func (it *Iterator) pushLeftChain(h *pnode) {
+	for h != nil {
+		it.stack = append(it.stack, h)
+		h = h.left
+	}
+}
+
+func (it *Iterator) pushRightChain(h *pnode) {
+	for h != nil {
+		it.stack = append(it.stack, h)
+		h = h.right
+	}
+}
+
+// Builder permits transient, in-place bulk mutation of a persistent map. A
+// node may be mutated directly only while it belongs to the builder that
+// last cloned it; any node reachable from a prior generation is cloned
+// before being touched, same as Set/Delete. Build materializes the result.
+type Builder struct {
+	root *pnode
+}
+
+// NewBuilder starts a new empty builder.
+func NewBuilder() *Builder { return &Builder{} }
+
+// NewBuilderFrom starts a builder seeded with m's tree. The first mutation
+// through b clones every node it touches, since those nodes still belong to
+// m's generation.
+func NewBuilderFrom(m *PersistentMap) *Builder {
+	if m == nil {
+		return &Builder{}
+	}
+	return &Builder{root: m.root}
+}
+
+// Set stores v at key k, mutating in place any node already owned by b.
+func (b *Builder) Set(k KType, v VType) *Builder {
+	b.root = pput(b.root, k, v, b, (PersistentMap{}).compare)
+	b.root.color = pblack
+	return b
+}
+
+// Delete removes key k, mutating in place any node already owned by b.
+func (b *Builder) Delete(k KType) *Builder {
+	if b.root == nil {
+		return b
+	}
+	b.root = pdelete(b.root, k, b, (PersistentMap{}).compare)
+	if b.root != nil {
+		b.root.color = pblack
+	}
+	return b
+}
+
+// Build materializes the builder's tree into an immutable *PersistentMap.
+// Further mutation through b clones rather than corrupting the returned map.
+func (b *Builder) Build() *PersistentMap {
+	// Release b's ownership of every node it owns, so later mutation
+	// through b clones them instead of writing into the returned tree. A
+	// node not owned by b never has a child owned by b.
+	stack := []*pnode{b.root}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if h == nil || h.owner != b {
+			continue
+		}
+		h.owner = nil
+		stack = append(stack, h.left, h.right)
+	}
+	return &PersistentMap{root: b.root}
+}
+
+// deletions
+
+func pmoveRedLeft(h *pnode, owner *Builder) *pnode {
+	pflipColors(h, owner)
+	if isPRed(h.right.left) {
+		h.right = protateRight(h.right, owner)
+		h = protateLeft(h, owner)
+		pflipColors(h, owner)
+	}
+	return h
+}
+
+func pmoveRedRight(h *pnode, owner *Builder) *pnode {
+	pflipColors(h, owner)
+	if isPRed(h.left.left) {
+		h = protateRight(h, owner)
+		pflipColors(h, owner)
+	}
+	return h
+}
+
+func pbalance(h *pnode, owner *Builder) *pnode {
+	if isPRed(h.right) {
+		h = protateLeft(h, owner)
+	}
+	if isPRed(h.left) && isPRed(h.left.left) {
+		h = protateRight(h, owner)
+	}
+	if isPRed(h.left) && isPRed(h.right) {
+		pflipColors(h, owner)
+	}
+	h.n = psize(h.left) + psize(h.right) + 1
+	return h
+}
+
+// nodes
+
+const (
+	pred   = true
+	pblack = false
+)
+
+// pnode is conceptually immutable: it is only ever mutated in place while
+// owner is non-nil and matches the Builder performing the mutation.
+type pnode struct {
+	key         KType
+	val         VType
+	left, right *pnode
+	n           int
+	color       bool
+	owner       *Builder
+}
+
+func newPnode(k KType, v VType, n int, color bool, owner *Builder) *pnode {
+	return &pnode{key: k, val: v, n: n, color: color, owner: owner}
+}
+
+// pclone returns h unchanged if it already belongs to owner's generation,
+// otherwise it returns a fresh copy so the mutation that follows never
+// touches a node reachable from another PersistentMap or Builder.
+func pclone(h *pnode, owner *Builder) *pnode {
+	if owner != nil && h.owner == owner {
+		return h
+	}
+	clone := *h
+	clone.owner = owner
+	return &clone
+}
+
+func isPRed(x *pnode) bool { return (x != nil) && (x.color == pred) }
+
+func protateLeft(h *pnode, owner *Builder) *pnode {
+	x := pclone(h.right, owner)
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = pred
+	x.n = h.n
+	h.n = 1 + psize(h.left) + psize(h.right)
+	return x
+}
+
+func protateRight(h *pnode, owner *Builder) *pnode {
+	x := pclone(h.left, owner)
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = pred
+	x.n = h.n
+	h.n = 1 + psize(h.left) + psize(h.right)
+	return x
+}
+
+func pflipColors(h *pnode, owner *Builder) {
+	h.left = pclone(h.left, owner)
+	h.right = pclone(h.right, owner)
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+func psize(x *pnode) int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
@@ -0,0 +1,465 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+// compare orders two keys. It is the single indirection point rewritten by
+// the generator for primitive key types, so every comparison in this file
+// must go through it rather than calling KType.Compare directly.
+func (r RedBlackSet) compare(a, b KType) int { return a.Compare(b) }
+
+// RedBlackSet is a sorted set built on a left leaning red black balanced
+// search tree. It stores KType elements, without a value slot.
+type RedBlackSet struct {
+	root *snode
+}
+
+// NewSet creates an empty sorted set.
+func NewSet() *RedBlackSet { return &RedBlackSet{} }
+
+// IsEmpty tells if the sorted set contains no elements.
+func (r RedBlackSet) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the sorted set.
+func (r RedBlackSet) Size() int { return ssize(r.root) }
+
+// Clear all the elements in the sorted set.
+func (r *RedBlackSet) Clear() { r.root = nil }
+
+// Add a key to the sorted set. Returns false if the key was already present.
+func (r *RedBlackSet) Add(k KType) (added bool) {
+	var existed bool
+	r.root, existed = sput(r.root, k, r.compare)
+	r.root.color = sblack
+	return !existed
+}
+
+func sput(h *snode, k KType, cmp func(a, b KType) int) (_ *snode, existed bool) {
+	if h == nil {
+		return newSnode(k, 1, sred), false
+	}
+
+	c := cmp(k, h.key)
+	if c < 0 {
+		h.left, existed = sput(h.left, k, cmp)
+	} else if c > 0 {
+		h.right, existed = sput(h.right, k, cmp)
+	} else {
+		existed = true
+	}
+
+	if isSRed(h.right) && !isSRed(h.left) {
+		h = srotateLeft(h)
+	}
+	if isSRed(h.left) && isSRed(h.left.left) {
+		h = srotateRight(h)
+	}
+	if isSRed(h.left) && isSRed(h.right) {
+		sflipColors(h)
+	}
+	h.n = ssize(h.left) + ssize(h.right) + 1
+	return h, existed
+}
+
+// Has tells if k is a member of the sorted set.
+func (r RedBlackSet) Has(k KType) bool {
+	h := r.root
+	for h != nil {
+		c := r.compare(k, h.key)
+		if c == 0 {
+			return true
+		} else if c < 0 {
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	return false
+}
+
+// Min returns the smallest key in the sorted set, if it exists.
+func (r RedBlackSet) Min() (k KType, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := smin(r.root)
+	return h.key, true
+}
+
+func smin(x *snode) *snode {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+// Max returns the largest key in the sorted set, if it exists.
+func (r RedBlackSet) Max() (k KType, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := smax(r.root)
+	return h.key, true
+}
+
+func smax(x *snode) *snode {
+	for x.right != nil {
+		x = x.right
+	}
+	return x
+}
+
+// Floor returns the largest key in the sorted set that is smaller than or
+// equal to k.
+func (r RedBlackSet) Floor(k KType) (KType, bool) {
+	x := sfloor(r.root, k, r.compare)
+	if x == nil {
+		var zero KType
+		return zero, false
+	}
+	return x.key, true
+}
+
+func sfloor(h *snode, k KType, cmp func(a, b KType) int) *snode {
+	if h == nil {
+		return nil
+	}
+	c := cmp(k, h.key)
+	if c == 0 {
+		return h
+	}
+	if c < 0 {
+		return sfloor(h.left, k, cmp)
+	}
+	t := sfloor(h.right, k, cmp)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Ceiling returns the smallest key in the sorted set that is larger than or
+// equal to k.
+func (r RedBlackSet) Ceiling(k KType) (KType, bool) {
+	x := sceiling(r.root, k, r.compare)
+	if x == nil {
+		var zero KType
+		return zero, false
+	}
+	return x.key, true
+}
+
+func sceiling(h *snode, k KType, cmp func(a, b KType) int) *snode {
+	if h == nil {
+		return nil
+	}
+	c := cmp(k, h.key)
+	if c == 0 {
+		return h
+	}
+	if c > 0 {
+		return sceiling(h.right, k, cmp)
+	}
+	t := sceiling(h.left, k, cmp)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Select returns the key of rank k, meaning the k-th smallest key in the set.
+func (r RedBlackSet) Select(k int) (KType, bool) {
+	x := snodeselect(r.root, k)
+	if x == nil {
+		var zero KType
+		return zero, false
+	}
+	return x.key, true
+}
+
+func snodeselect(x *snode, k int) *snode {
+	if x == nil {
+		return nil
+	}
+	t := ssize(x.left)
+	if t > k {
+		return snodeselect(x.left, k)
+	} else if t < k {
+		return snodeselect(x.right, k-t-1)
+	}
+	return x
+}
+
+// Rank is the number of keys less than k.
+func (r RedBlackSet) Rank(k KType) int {
+	return skeyrank(k, r.root, r.compare)
+}
+
+func skeyrank(k KType, h *snode, cmp func(a, b KType) int) int {
+	if h == nil {
+		return 0
+	}
+	c := cmp(k, h.key)
+	if c < 0 {
+		return skeyrank(k, h.left, cmp)
+	} else if c > 0 {
+		return 1 + ssize(h.left) + skeyrank(k, h.right, cmp)
+	}
+	return ssize(h.left)
+}
+
+// Range visits every key between lo and hi in the sorted set, in order.
+// It stops when visit returns false.
+func (r RedBlackSet) Range(lo, hi KType, visit func(KType) bool) {
+	srange(r.root, visit, lo, hi, r.compare)
+}
+
+func srange(h *snode, visit func(KType) bool, lo, hi KType, cmp func(a, b KType) int) bool {
+	if h == nil {
+		return true
+	}
+	cmplo := cmp(lo, h.key)
+	cmphi := cmp(hi, h.key)
+	if cmplo < 0 {
+		if !srange(h.left, visit, lo, hi, cmp) {
+			return false
+		}
+	}
+	if cmplo <= 0 && cmphi >= 0 {
+		if !visit(h.key) {
+			return false
+		}
+	}
+	if cmphi > 0 {
+		if !srange(h.right, visit, lo, hi, cmp) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set containing every key in r or other.
+func (r RedBlackSet) Union(other *RedBlackSet) *RedBlackSet {
+	out := NewSet()
+	sinorder(r.root, func(k KType) bool { out.Add(k); return true })
+	if other != nil {
+		sinorder(other.root, func(k KType) bool { out.Add(k); return true })
+	}
+	return out
+}
+
+// Intersection returns a new set containing every key present in both r and
+// other.
+func (r RedBlackSet) Intersection(other *RedBlackSet) *RedBlackSet {
+	out := NewSet()
+	if other == nil {
+		return out
+	}
+	sinorder(r.root, func(k KType) bool {
+		if other.Has(k) {
+			out.Add(k)
+		}
+		return true
+	})
+	return out
+}
+
+// Difference returns a new set containing every key present in r but not in
+// other.
+func (r RedBlackSet) Difference(other *RedBlackSet) *RedBlackSet {
+	out := NewSet()
+	sinorder(r.root, func(k KType) bool {
+		if other == nil || !other.Has(k) {
+			out.Add(k)
+		}
+		return true
+	})
+	return out
+}
+
+// SubsetOf tells if every key in r is also present in other.
+func (r RedBlackSet) SubsetOf(other *RedBlackSet) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+	subset := true
+	sinorder(r.root, func(k KType) bool {
+		if !other.Has(k) {
+			subset = false
+			return false
+		}
+		return true
+	})
+	return subset
+}
+
+// sinorder visits every key in the subtree rooted at h, in order, stopping
+// when visit returns false.
+func sinorder(h *snode, visit func(KType) bool) bool {
+	if h == nil {
+		return true
+	}
+	if !sinorder(h.left, visit) {
+		return false
+	}
+	if !visit(h.key) {
+		return false
+	}
+	return sinorder(h.right, visit)
+}
+
+// Delete removes k from the sorted set, if it exists.
+func (r *RedBlackSet) Delete(k KType) (ok bool) {
+	if r.root == nil {
+		return
+	}
+	r.root, ok = sdelete(r.root, k, r.compare)
+	if !r.IsEmpty() {
+		r.root.color = sblack
+	}
+	return
+}
+
+func sdelete(h *snode, k KType, cmp func(a, b KType) int) (_ *snode, ok bool) {
+	if h == nil {
+		return h, false
+	}
+
+	if cmp(k, h.key) < 0 {
+		if h.left == nil {
+			return h, false
+		}
+		if !isSRed(h.left) && !isSRed(h.left.left) {
+			h = smoveRedLeft(h)
+		}
+		h.left, ok = sdelete(h.left, k, cmp)
+		return sbalance(h), ok
+	}
+
+	if isSRed(h.left) {
+		h = srotateRight(h)
+	}
+
+	if cmp(k, h.key) == 0 && h.right == nil {
+		return nil, true
+	}
+
+	if h.right != nil && !isSRed(h.right) && !isSRed(h.right.left) {
+		h = smoveRedRight(h)
+	}
+
+	if cmp(k, h.key) == 0 {
+		h.key = smin(h.right).key
+		h.right = sdeleteMin(h.right)
+		ok = true
+	} else {
+		h.right, ok = sdelete(h.right, k, cmp)
+	}
+
+	return sbalance(h), ok
+}
+
+func sdeleteMin(h *snode) *snode {
+	if h.left == nil {
+		return nil
+	}
+	if !isSRed(h.left) && !isSRed(h.left.left) {
+		h = smoveRedLeft(h)
+	}
+	h.left = sdeleteMin(h.left)
+	return sbalance(h)
+}
+
+// deletions
+
+func smoveRedLeft(h *snode) *snode {
+	sflipColors(h)
+	if isSRed(h.right.left) {
+		h.right = srotateRight(h.right)
+		h = srotateLeft(h)
+		sflipColors(h)
+	}
+	return h
+}
+
+func smoveRedRight(h *snode) *snode {
+	sflipColors(h)
+	if isSRed(h.left.left) {
+		h = srotateRight(h)
+		sflipColors(h)
+	}
+	return h
+}
+
+func sbalance(h *snode) *snode {
+	if isSRed(h.right) {
+		h = srotateLeft(h)
+	}
+	if isSRed(h.left) && isSRed(h.left.left) {
+		h = srotateRight(h)
+	}
+	if isSRed(h.left) && isSRed(h.right) {
+		sflipColors(h)
+	}
+	h.n = ssize(h.left) + ssize(h.right) + 1
+	return h
+}
+
+// nodes
+
+const (
+	sred   = true
+	sblack = false
+)
+
+type snode struct {
+	key         KType
+	left, right *snode
+	n           int
+	color       bool
+}
+
+func newSnode(k KType, n int, color bool) *snode {
+	return &snode{key: k, n: n, color: color}
+}
+
+func isSRed(x *snode) bool { return (x != nil) && (x.color == sred) }
+
+func srotateLeft(h *snode) *snode {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.color = h.color
+	h.color = sred
+	x.n = h.n
+	h.n = 1 + ssize(h.left) + ssize(h.right)
+	return x
+}
+
+func srotateRight(h *snode) *snode {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.color = h.color
+	h.color = sred
+	x.n = h.n
+	h.n = 1 + ssize(h.left) + ssize(h.right)
+	return x
+}
+
+func sflipColors(h *snode) {
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+func ssize(x *snode) int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}